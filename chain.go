@@ -0,0 +1,133 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+package evmsignature
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/zeebo/errs"
+)
+
+// ErrChainRegistry indicates that chain registry error.
+var ErrChainRegistry = errs.Class("chain registry error")
+
+// ChainInfo describes a single chain known to the registry.
+type ChainInfo struct {
+	Name           Chain
+	ID             *big.Int
+	NativeSymbol   string
+	NativeDecimals int
+	BlockExplorer  string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]ChainInfo)
+)
+
+func init() {
+	for _, ci := range []ChainInfo{
+		{Name: ChainEthereum, ID: big.NewInt(1), NativeSymbol: "ETH", NativeDecimals: 18, BlockExplorer: "https://etherscan.io"},
+		{Name: ChainRopsten, ID: big.NewInt(3), NativeSymbol: "ETH", NativeDecimals: 18, BlockExplorer: "https://ropsten.etherscan.io"},
+		{Name: ChainRinkeby, ID: big.NewInt(4), NativeSymbol: "ETH", NativeDecimals: 18, BlockExplorer: "https://rinkeby.etherscan.io"},
+		{Name: ChainGoerli, ID: big.NewInt(5), NativeSymbol: "ETH", NativeDecimals: 18, BlockExplorer: "https://goerli.etherscan.io"},
+		{Name: ChainPolygon, ID: big.NewInt(137), NativeSymbol: "MATIC", NativeDecimals: 18, BlockExplorer: "https://polygonscan.com"},
+		{Name: ChainMumbai, ID: big.NewInt(80001), NativeSymbol: "MATIC", NativeDecimals: 18, BlockExplorer: "https://mumbai.polygonscan.com"},
+		{Name: ChainBSC, ID: big.NewInt(56), NativeSymbol: "BNB", NativeDecimals: 18, BlockExplorer: "https://bscscan.com"},
+		{Name: ChainArbitrum, ID: big.NewInt(42161), NativeSymbol: "ETH", NativeDecimals: 18, BlockExplorer: "https://arbiscan.io"},
+		{Name: ChainOptimism, ID: big.NewInt(10), NativeSymbol: "ETH", NativeDecimals: 18, BlockExplorer: "https://optimistic.etherscan.io"},
+		{Name: ChainAvalanche, ID: big.NewInt(43114), NativeSymbol: "AVAX", NativeDecimals: 18, BlockExplorer: "https://snowtrace.io"},
+	} {
+		Register(ci)
+	}
+}
+
+// Register adds or replaces a chain in the default registry.
+func Register(ci ChainInfo) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[ci.ID.String()] = ci
+}
+
+// Lookup returns the registered ChainInfo for id, if any.
+func Lookup(id *big.Int) (ChainInfo, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	ci, ok := registry[id.String()]
+	return ci, ok
+}
+
+// ToBase converts amount expressed in the smallest unit (e.g. wei) to its base
+// denomination (e.g. ether), given the number of decimals of that
+// denomination, as an exact rational amount/10^decimals. big.Rat is used
+// instead of big.Float because a binary float cannot represent a base-10
+// fraction like 1/10^18 exactly at any precision, which silently loses wei
+// for large amounts.
+func ToBase(amount *big.Int, decimals int) *big.Rat {
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Rat).SetFrac(amount, divisor)
+}
+
+// FromBase converts amount expressed in a base denomination (e.g. ether) back
+// to its smallest unit (e.g. wei), given the number of decimals of that
+// denomination. If amount doesn't divide evenly into the smallest unit, the
+// result is truncated toward zero.
+func FromBase(amount *big.Rat, decimals int) (*big.Int, error) {
+	if amount == nil {
+		return nil, ErrChainRegistry.New("amount must not be nil")
+	}
+
+	multiplier := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	scaled := new(big.Rat).Mul(amount, new(big.Rat).SetInt(multiplier))
+
+	return new(big.Int).Quo(scaled.Num(), scaled.Denom()), nil
+}
+
+// FormatUnits renders amount, expressed in the smallest unit, as a decimal
+// string with up to decimals fractional digits, trimming trailing zeros and
+// handling negative values.
+func FormatUnits(amount *big.Int, decimals int) string {
+	negative := amount.Sign() < 0
+
+	digits := new(big.Int).Abs(amount).String()
+	if decimals > 0 {
+		for len(digits) <= decimals {
+			digits = "0" + digits
+		}
+
+		intPart := digits[:len(digits)-decimals]
+		fracPart := strings.TrimRight(digits[len(digits)-decimals:], "0")
+		if fracPart == "" {
+			digits = intPart
+		} else {
+			digits = intPart + "." + fracPart
+		}
+	}
+
+	if negative {
+		digits = "-" + digits
+	}
+
+	return digits
+}
+
+// EncodeV applies EIP-155 replay protection, folding recoveryID (0 or 1) and
+// chainID into a single v value: v = chainID*2 + 35 + recoveryID.
+func EncodeV(recoveryID byte, chainID *big.Int) *big.Int {
+	v := new(big.Int).Mul(chainID, big.NewInt(2))
+	return v.Add(v, big.NewInt(35+int64(recoveryID)))
+}
+
+// DecodeV reverses EncodeV, extracting the recovery id and chain id from an EIP-155 v value.
+func DecodeV(v *big.Int) (recoveryID byte, chainID *big.Int) {
+	tmp := new(big.Int).Sub(v, big.NewInt(35))
+	chainID = new(big.Int).Div(tmp, big.NewInt(2))
+	recoveryID = byte(new(big.Int).Mod(tmp, big.NewInt(2)).Int64())
+
+	return recoveryID, chainID
+}
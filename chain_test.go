@@ -0,0 +1,61 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+package evmsignature_test
+
+import (
+	"math/big"
+	"testing"
+
+	evmsignature "github.com/BoostyLabs/evmsignature"
+)
+
+func TestToBaseFromBaseRoundTrip(t *testing.T) {
+	wei, ok := new(big.Int).SetString("340282366920938463463374607431768211455", 10) // 2^128 - 1.
+	if !ok {
+		t.Fatal("failed to parse test value")
+	}
+
+	got, err := evmsignature.FromBase(evmsignature.ToBase(wei, 18), 18)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Cmp(wei) != 0 {
+		t.Fatalf("round trip lost precision: got %s, want %s", got, wei)
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	for _, tc := range []struct {
+		amount   *big.Int
+		decimals int
+		want     string
+	}{
+		{big.NewInt(1000000000000000000), 18, "1"},
+		{big.NewInt(1500000000000000000), 18, "1.5"},
+		{big.NewInt(-1500000000000000000), 18, "-1.5"},
+		{big.NewInt(1), 18, "0.000000000000000001"},
+		{big.NewInt(100), 0, "100"},
+	} {
+		if got := evmsignature.FormatUnits(tc.amount, tc.decimals); got != tc.want {
+			t.Errorf("FormatUnits(%s, %d) = %q, want %q", tc.amount, tc.decimals, got, tc.want)
+		}
+	}
+}
+
+func TestEncodeDecodeV(t *testing.T) {
+	chainID := big.NewInt(1)
+
+	for recoveryID := byte(0); recoveryID <= 1; recoveryID++ {
+		v := evmsignature.EncodeV(recoveryID, chainID)
+
+		gotRecoveryID, gotChainID := evmsignature.DecodeV(v)
+		if gotRecoveryID != recoveryID {
+			t.Errorf("DecodeV(%s) recoveryID = %d, want %d", v, gotRecoveryID, recoveryID)
+		}
+		if gotChainID.Cmp(chainID) != 0 {
+			t.Errorf("DecodeV(%s) chainID = %s, want %s", v, gotChainID, chainID)
+		}
+	}
+}
@@ -5,7 +5,6 @@ package evmsignature
 
 import (
 	"fmt"
-	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/zeebo/errs"
@@ -81,17 +80,28 @@ const (
 	ChainPolygon Chain = "polygon"
 	// ChainRopsten indicates that chain is ropsten.
 	ChainRopsten Chain = "ropsten"
+	// ChainRinkeby indicates that chain is rinkeby.
+	ChainRinkeby Chain = "rinkeby"
+	// ChainGoerli indicates that chain is goerli.
+	ChainGoerli Chain = "goerli"
+	// ChainMumbai indicates that chain is mumbai.
+	ChainMumbai Chain = "mumbai"
+	// ChainBSC indicates that chain is binance smart chain.
+	ChainBSC Chain = "bsc"
+	// ChainArbitrum indicates that chain is arbitrum.
+	ChainArbitrum Chain = "arbitrum"
+	// ChainOptimism indicates that chain is optimism.
+	ChainOptimism Chain = "optimism"
+	// ChainAvalanche indicates that chain is avalanche.
+	ChainAvalanche Chain = "avalanche"
 )
 
-// ChainID defines the list of possible number chains in blockchain.
-type ChainID int
-
-const (
-	// ChainIDRinkeby indicates that chain id is 4.
-	ChainIDRinkeby ChainID = 4
-	// ChainIDMatic indicates that chain id is 137.
-	ChainIDMatic ChainID = 137
-)
+// ChainID, WeiInEthereum and the Wei*/EthereumFloat* helpers that used to
+// live here were superseded by the chain registry (chain.go) and the
+// decimal-aware ToBase/FromBase/FormatUnits conversions, which support any
+// registered chain and decimal count without the float precision loss the
+// old helpers had. See ChainInfo, Register, Lookup, ToBase, FromBase and
+// FormatUnits.
 
 // Length defines the list of possible lengths of blockchain elements.
 type Length int
@@ -142,27 +152,3 @@ type Contract struct {
 	Address       Address `json:"address"`
 	AddressMethod Hex     `json:"addressMethod"`
 }
-
-// WeiInEthereum indicates that one ether = 1,000,000,000,000,000,000 wei (10^18).
-const WeiInEthereum = 1000000000000000000
-
-// WeiBigToEthereumBig converts wei to ethereum coins.
-func WeiBigToEthereumBig(value *big.Int) *big.Int {
-	return new(big.Int).Div(value, new(big.Int).SetInt64(WeiInEthereum))
-}
-
-// WeiBigToEthereumFloat converts wei to ethereum coins.
-func WeiBigToEthereumFloat(value *big.Int) float64 {
-	f, _ := new(big.Float).Quo(new(big.Float).SetInt(value), new(big.Float).SetInt64(WeiInEthereum)).Float64()
-	return f
-}
-
-// EthereumFloatToWeiBig converts ethereum coins to wei.
-func EthereumFloatToWeiBig(value float64) (*big.Int, error) {
-	result, ok := new(big.Int).SetString(fmt.Sprintf("%.0f", value*WeiInEthereum), 10)
-	if !ok {
-		return result, errs.New("invalid value")
-	}
-
-	return result, nil
-}
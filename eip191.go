@@ -0,0 +1,42 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+package evmsignature
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// personalMessagePrefix is prepended to every EIP-191 personal_sign payload before hashing.
+const personalMessagePrefix = "\x19Ethereum Signed Message:\n"
+
+// SignWithVersion builds and signs an EIP-191 payload of the form
+// 0x19 <version> <versionData> <payload>, returning keccak256 of the framed
+// payload signed by pk. version 0x00 is the "validator" form (versionData is
+// the validator address) and 0x45 ('E') is the personal_sign form.
+func SignWithVersion(version byte, versionData, payload []byte, pk PrivateKey) ([]byte, error) {
+	framed := append([]byte{0x19, version}, versionData...)
+	framed = append(framed, payload...)
+
+	return sign(crypto.Keccak256(framed), pk)
+}
+
+// SignPersonalMessage signs msg the way personal_sign / eth_sign does: it
+// prepends "\x19Ethereum Signed Message:\n" + len(msg) before keccak256+ECDSA.
+func SignPersonalMessage(msg []byte, pk PrivateKey) ([]byte, error) {
+	return sign(personalMessageHash(msg), pk)
+}
+
+// RecoverPersonalSigner recovers the address that produced sig over msg under
+// the personal_sign / eth_sign framing.
+func RecoverPersonalSigner(msg, sig []byte) (Address, error) {
+	return recoverSigner(personalMessageHash(msg), sig)
+}
+
+// personalMessageHash returns keccak256("\x19Ethereum Signed Message:\n" + len(msg) + msg).
+func personalMessageHash(msg []byte) []byte {
+	prefixed := append([]byte(fmt.Sprintf("%s%d", personalMessagePrefix, len(msg))), msg...)
+	return crypto.Keccak256(prefixed)
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+package evmsignature_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	evmsignature "github.com/BoostyLabs/evmsignature"
+)
+
+func TestSignPersonalMessageRoundTrip(t *testing.T) {
+	privateKeyECDSA, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pk := evmsignature.PrivateKey(hex.EncodeToString(crypto.FromECDSA(privateKeyECDSA)))
+	wantAddress := evmsignature.Address(crypto.PubkeyToAddress(privateKeyECDSA.PublicKey).Hex())
+
+	msg := []byte("Hello, Bob!")
+
+	sig, err := evmsignature.SignPersonalMessage(msg, pk)
+	if err != nil {
+		t.Fatalf("unexpected error signing: %v", err)
+	}
+
+	gotAddress, err := evmsignature.RecoverPersonalSigner(msg, sig)
+	if err != nil {
+		t.Fatalf("unexpected error recovering: %v", err)
+	}
+
+	if gotAddress != wantAddress {
+		t.Fatalf("RecoverPersonalSigner = %s, want %s", gotAddress, wantAddress)
+	}
+}
+
+func TestSignWithVersionValidator(t *testing.T) {
+	privateKeyECDSA, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pk := evmsignature.PrivateKey(hex.EncodeToString(crypto.FromECDSA(privateKeyECDSA)))
+
+	versionData := crypto.PubkeyToAddress(privateKeyECDSA.PublicKey).Bytes()
+	payload := []byte("approve")
+
+	sig, err := evmsignature.SignWithVersion(0x00, versionData, payload, pk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sig) != 65 {
+		t.Fatalf("signature length = %d, want 65", len(sig))
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+// Package keystore imports and exports PrivateKey values as Web3 Secret
+// Storage v3 JSON keystore files, compatible with geth, MetaMask and similar tools.
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/zeebo/errs"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+
+	evmsignature "github.com/BoostyLabs/evmsignature"
+)
+
+// ErrKeystore indicates that keystore error.
+var ErrKeystore = errs.Class("keystore error")
+
+// keystoreVersion is the only Web3 Secret Storage version this package understands.
+const keystoreVersion = 3
+
+// ScryptParams configures the scrypt KDF used to derive the keystore encryption key.
+type ScryptParams struct {
+	N     int
+	R     int
+	P     int
+	DKLen int
+}
+
+// DefaultScryptParams are the parameters geth uses for interactive (non-light) keystores.
+var DefaultScryptParams = ScryptParams{N: 1 << 18, R: 8, P: 1, DKLen: 32}
+
+type cipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+type cryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams cipherParamsJSON       `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type keystoreJSON struct {
+	Address string     `json:"address"`
+	Crypto  cryptoJSON `json:"crypto"`
+	Id      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+// DecryptKeystore parses a Web3 Secret Storage v3 JSON keystore and recovers
+// the private key and address it protects. The passphrase is run through the
+// keystore's configured KDF (scrypt or PBKDF2-HMAC-SHA256) against its salt to
+// derive the decryption key, which is then verified against the stored MAC
+// before AES-128-CTR-decrypting the ciphertext.
+func DecryptKeystore(jsonBytes []byte, passphrase string) (evmsignature.PrivateKey, evmsignature.Address, error) {
+	var ks keystoreJSON
+	if err := json.Unmarshal(jsonBytes, &ks); err != nil {
+		return "", "", ErrKeystore.Wrap(err)
+	}
+
+	if ks.Version != keystoreVersion {
+		return "", "", ErrKeystore.New("unsupported keystore version %d", ks.Version)
+	}
+	if ks.Crypto.Cipher != "aes-128-ctr" {
+		return "", "", ErrKeystore.New("unsupported cipher %q", ks.Crypto.Cipher)
+	}
+
+	derivedKey, err := deriveKey(ks.Crypto, passphrase)
+	if err != nil {
+		return "", "", err
+	}
+
+	cipherText, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return "", "", ErrKeystore.Wrap(err)
+	}
+
+	expectedMAC, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return "", "", ErrKeystore.Wrap(err)
+	}
+
+	mac := crypto.Keccak256(append(derivedKey[16:32], cipherText...))
+	if subtle.ConstantTimeCompare(mac, expectedMAC) != 1 {
+		return "", "", ErrKeystore.New("invalid passphrase")
+	}
+
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil {
+		return "", "", ErrKeystore.Wrap(err)
+	}
+
+	block, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		return "", "", ErrKeystore.Wrap(err)
+	}
+
+	secret := make([]byte, len(cipherText))
+	cipher.NewCTR(block, iv).XORKeyStream(secret, cipherText)
+
+	pk := evmsignature.PrivateKey(hex.EncodeToString(secret))
+	if !pk.IsValidPrivateKey() {
+		return "", "", ErrKeystore.New("decrypted key is not a valid private key")
+	}
+
+	privateKeyECDSA, err := crypto.HexToECDSA(string(pk))
+	if err != nil {
+		return "", "", ErrKeystore.Wrap(err)
+	}
+
+	return pk, evmsignature.Address(crypto.PubkeyToAddress(privateKeyECDSA.PublicKey).Hex()), nil
+}
+
+// EncryptKeystore encrypts pk into a Web3 Secret Storage v3 JSON keystore
+// protected by passphrase, deriving the encryption key with scrypt under params.
+func EncryptKeystore(pk evmsignature.PrivateKey, passphrase string, params ScryptParams) ([]byte, error) {
+	if !pk.IsValidPrivateKey() {
+		return nil, ErrKeystore.New("invalid private key")
+	}
+
+	privateKeyECDSA, err := crypto.HexToECDSA(string(pk))
+	if err != nil {
+		return nil, ErrKeystore.Wrap(err)
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, ErrKeystore.Wrap(err)
+	}
+
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, ErrKeystore.Wrap(err)
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, ErrKeystore.Wrap(err)
+	}
+
+	secret, err := hex.DecodeString(string(pk))
+	if err != nil {
+		return nil, ErrKeystore.Wrap(err)
+	}
+
+	block, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		return nil, ErrKeystore.Wrap(err)
+	}
+
+	cipherText := make([]byte, len(secret))
+	cipher.NewCTR(block, iv).XORKeyStream(cipherText, secret)
+
+	mac := crypto.Keccak256(append(derivedKey[16:32], cipherText...))
+	address := crypto.PubkeyToAddress(privateKeyECDSA.PublicKey)
+
+	ks := keystoreJSON{
+		Address: address.Hex()[2:],
+		Crypto: cryptoJSON{
+			Cipher:       "aes-128-ctr",
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          "scrypt",
+			KDFParams: map[string]interface{}{
+				"n":     params.N,
+				"r":     params.R,
+				"p":     params.P,
+				"dklen": params.DKLen,
+				"salt":  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(mac),
+		},
+		Id:      uuid.New().String(),
+		Version: keystoreVersion,
+	}
+
+	return json.Marshal(ks)
+}
+
+// deriveKey runs the passphrase through c's configured KDF (scrypt or PBKDF2-HMAC-SHA256).
+func deriveKey(c cryptoJSON, passphrase string) ([]byte, error) {
+	salt, err := hex.DecodeString(fmt.Sprint(c.KDFParams["salt"]))
+	if err != nil {
+		return nil, ErrKeystore.Wrap(err)
+	}
+
+	dkLen := 32
+	if v, ok := c.KDFParams["dklen"].(float64); ok {
+		dkLen = int(v)
+	}
+	if dkLen < 32 {
+		return nil, ErrKeystore.New("dklen must be at least 32, got %d", dkLen)
+	}
+
+	switch c.KDF {
+	case "scrypt":
+		n, _ := c.KDFParams["n"].(float64)
+		r, _ := c.KDFParams["r"].(float64)
+		p, _ := c.KDFParams["p"].(float64)
+		if n < 2 || r < 1 || p < 1 {
+			return nil, ErrKeystore.New("invalid scrypt parameters n=%v r=%v p=%v", n, r, p)
+		}
+
+		key, err := scrypt.Key([]byte(passphrase), salt, int(n), int(r), int(p), dkLen)
+		if err != nil {
+			return nil, ErrKeystore.Wrap(err)
+		}
+		return key, nil
+
+	case "pbkdf2":
+		c1, _ := c.KDFParams["c"].(float64)
+		if c1 < 1 {
+			return nil, ErrKeystore.New("invalid pbkdf2 iteration count %v", c1)
+		}
+		return pbkdf2.Key([]byte(passphrase), salt, int(c1), dkLen, sha256.New), nil
+
+	default:
+		return nil, ErrKeystore.New("unsupported kdf %q", c.KDF)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+package keystore_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	gethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+
+	evmsignature "github.com/BoostyLabs/evmsignature"
+	"github.com/BoostyLabs/evmsignature/keystore"
+)
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	privateKeyECDSA, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pk := evmsignature.PrivateKey(hex.EncodeToString(crypto.FromECDSA(privateKeyECDSA)))
+	wantAddress := evmsignature.Address(crypto.PubkeyToAddress(privateKeyECDSA.PublicKey).Hex())
+
+	// Use light scrypt params so the test doesn't pay the interactive (2^18) cost.
+	params := keystore.ScryptParams{N: 1 << 12, R: 8, P: 1, DKLen: 32}
+
+	jsonBytes, err := keystore.EncryptKeystore(pk, "correct horse battery staple", params)
+	if err != nil {
+		t.Fatalf("unexpected error encrypting: %v", err)
+	}
+
+	gotPK, gotAddress, err := keystore.DecryptKeystore(jsonBytes, "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("unexpected error decrypting: %v", err)
+	}
+
+	if gotPK != pk {
+		t.Fatalf("DecryptKeystore private key = %s, want %s", gotPK, pk)
+	}
+	if gotAddress != wantAddress {
+		t.Fatalf("DecryptKeystore address = %s, want %s", gotAddress, wantAddress)
+	}
+}
+
+// TestEncryptKeystoreInteropWithGeth checks that EncryptKeystore's output can
+// be decrypted by go-ethereum's own keystore parser, which - unlike this
+// package's DecryptKeystore - requires a well-formed "id" UUID and would
+// reject a keystore missing or mangling it.
+func TestEncryptKeystoreInteropWithGeth(t *testing.T) {
+	privateKeyECDSA, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pk := evmsignature.PrivateKey(hex.EncodeToString(crypto.FromECDSA(privateKeyECDSA)))
+	wantAddress := crypto.PubkeyToAddress(privateKeyECDSA.PublicKey)
+
+	params := keystore.ScryptParams{N: 1 << 12, R: 8, P: 1, DKLen: 32}
+
+	jsonBytes, err := keystore.EncryptKeystore(pk, "correct horse battery staple", params)
+	if err != nil {
+		t.Fatalf("unexpected error encrypting: %v", err)
+	}
+
+	key, err := gethkeystore.DecryptKey(jsonBytes, "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("go-ethereum failed to decrypt our keystore: %v", err)
+	}
+
+	if _, err := uuid.Parse(key.Id.String()); err != nil {
+		t.Fatalf("keystore id is not a well-formed UUID: %v", err)
+	}
+	if key.Address != wantAddress {
+		t.Fatalf("go-ethereum recovered address = %s, want %s", key.Address, wantAddress)
+	}
+	if hex.EncodeToString(crypto.FromECDSA(key.PrivateKey)) != string(pk) {
+		t.Fatal("go-ethereum recovered a different private key than was encrypted")
+	}
+}
+
+func TestDecryptKeystoreWrongPassphrase(t *testing.T) {
+	privateKeyECDSA, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pk := evmsignature.PrivateKey(hex.EncodeToString(crypto.FromECDSA(privateKeyECDSA)))
+	params := keystore.ScryptParams{N: 1 << 12, R: 8, P: 1, DKLen: 32}
+
+	jsonBytes, err := keystore.EncryptKeystore(pk, "correct passphrase", params)
+	if err != nil {
+		t.Fatalf("unexpected error encrypting: %v", err)
+	}
+
+	if _, _, err := keystore.DecryptKeystore(jsonBytes, "wrong passphrase"); err == nil {
+		t.Fatal("expected an error for a wrong passphrase, got nil")
+	}
+}
+
+// TestDecryptKeystoreMalformedKDFParams covers keystore files whose kdfparams
+// are corrupted or hand-crafted to be unreasonably small: DecryptKeystore must
+// return an error rather than let the value reach scrypt.Key/pbkdf2.Key, which
+// panic on out-of-range inputs instead of erroring.
+func TestDecryptKeystoreMalformedKDFParams(t *testing.T) {
+	const template = `{
+		"address": "0000000000000000000000000000000000000000",
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"ciphertext": "00",
+			"cipherparams": {"iv": "00000000000000000000000000000000"},
+			"kdf": "%s",
+			"kdfparams": %s,
+			"mac": "00"
+		},
+		"version": 3
+	}`
+
+	for _, tc := range []struct {
+		name      string
+		kdf       string
+		kdfparams string
+	}{
+		{"pbkdf2 dklen zero", "pbkdf2", `{"dklen": 0, "c": 1, "salt": "00"}`},
+		{"pbkdf2 iterations zero", "pbkdf2", `{"dklen": 32, "c": 0, "salt": "00"}`},
+		{"scrypt dklen too small", "scrypt", `{"dklen": 1, "n": 2, "r": 8, "p": 1, "salt": "00"}`},
+		{"scrypt n zero", "scrypt", `{"dklen": 32, "n": 0, "r": 8, "p": 1, "salt": "00"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			jsonBytes := []byte(fmt.Sprintf(template, tc.kdf, tc.kdfparams))
+
+			if _, _, err := keystore.DecryptKeystore(jsonBytes, "whatever"); err == nil {
+				t.Fatal("expected an error for malformed kdfparams, got nil")
+			}
+		})
+	}
+}
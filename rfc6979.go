@@ -0,0 +1,146 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+package evmsignature
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/zeebo/errs"
+)
+
+// ErrDeterministicSign indicates that deterministic sign error.
+var ErrDeterministicSign = errs.Class("deterministic sign error")
+
+// secp256k1N is the order of the secp256k1 base point.
+var secp256k1N = secp256k1.S256().Params().N
+
+// secp256k1HalfOrder is half of the secp256k1 curve order, used for low-S normalization.
+var secp256k1HalfOrder = new(big.Int).Rsh(secp256k1N, 1)
+
+// SignDeterministic signs hash (a 32-byte digest, e.g. keccak256(message))
+// with pk using an RFC 6979 HMAC-DRBG(SHA-256) nonce: the nonce k is derived
+// from pk and hash alone, independently of whatever secp256k1 implementation
+// happens to back sign()/crypto.Sign, so the same (pk, hash) pair always
+// yields the same (r, s, v) reproducibly across backends and libraries. The
+// result is normalized to low-S, flipping the recovery bit when s > n/2, and
+// v follows the package's PrivateKeyVTwentySeven/Eight convention.
+func SignDeterministic(hash []byte, pk PrivateKey) (r, s *big.Int, v PrivateKeyV, err error) {
+	if len(hash) != 32 {
+		return nil, nil, 0, ErrDeterministicSign.New("hash must be 32 bytes, got %d", len(hash))
+	}
+
+	d, ok := new(big.Int).SetString(string(pk), 16)
+	if !ok || d.Sign() <= 0 || d.Cmp(secp256k1N) >= 0 {
+		return nil, nil, 0, ErrDeterministicSign.New("invalid private key")
+	}
+
+	curve := secp256k1.S256()
+	z := new(big.Int).SetBytes(hash)
+	gen := newRFC6979Nonce(int2octets(d, 32), hash)
+
+	for {
+		k := gen.next()
+		if k.Sign() == 0 || k.Cmp(secp256k1N) >= 0 {
+			continue
+		}
+
+		x, y := curve.ScalarBaseMult(k.Bytes())
+
+		r = new(big.Int).Mod(x, secp256k1N)
+		if r.Sign() == 0 {
+			continue
+		}
+
+		kInv := new(big.Int).ModInverse(k, secp256k1N)
+		s = new(big.Int).Mul(r, d)
+		s.Add(s, z)
+		s.Mul(s, kInv)
+		s.Mod(s, secp256k1N)
+		if s.Sign() == 0 {
+			continue
+		}
+
+		recoveryID := byte(y.Bit(0))
+		if s.Cmp(secp256k1HalfOrder) > 0 {
+			s = new(big.Int).Sub(secp256k1N, s)
+			recoveryID ^= 1
+		}
+
+		return r, s, PrivateKeyVTwentySeven + PrivateKeyV(recoveryID), nil
+	}
+}
+
+// rfc6979Nonce generates successive RFC 6979 (section 3.2) candidate nonces
+// from an HMAC-DRBG(SHA-256) seeded with the private key and message hash.
+// secp256k1's order and SHA-256's output are both 256 bits, so each
+// generation step produces exactly one 32-byte candidate without the
+// multi-round padding RFC 6979 needs for mismatched bit lengths.
+type rfc6979Nonce struct {
+	v, k []byte
+}
+
+func newRFC6979Nonce(privateKey, hash []byte) *rfc6979Nonce {
+	h1 := bits2octets(hash)
+
+	v := bytes.Repeat([]byte{0x01}, sha256.Size)
+	k := bytes.Repeat([]byte{0x00}, sha256.Size)
+
+	k = hmacSHA256(k, v, []byte{0x00}, privateKey, h1)
+	v = hmacSHA256(k, v)
+	k = hmacSHA256(k, v, []byte{0x01}, privateKey, h1)
+	v = hmacSHA256(k, v)
+
+	return &rfc6979Nonce{v: v, k: k}
+}
+
+// next returns the next candidate nonce and advances the DRBG state, so a
+// rejected candidate (k == 0 or k >= n, or a resulting r or s of 0) can be
+// retried with a fresh one, per RFC 6979 section 3.2(h).
+func (n *rfc6979Nonce) next() *big.Int {
+	n.v = hmacSHA256(n.k, n.v)
+	t := n.v
+
+	n.k = hmacSHA256(n.k, n.v, []byte{0x00})
+	n.v = hmacSHA256(n.k, n.v)
+
+	return new(big.Int).SetBytes(t)
+}
+
+// bits2octets implements RFC 6979 section 2.3.4 for a 256-bit curve order:
+// reduce hash as an integer mod secp256k1N, then re-encode as 32 bytes.
+func bits2octets(hash []byte) []byte {
+	z := new(big.Int).SetBytes(hash)
+	if z.Cmp(secp256k1N) >= 0 {
+		z.Sub(z, secp256k1N)
+	}
+
+	return int2octets(z, 32)
+}
+
+// int2octets implements RFC 6979 section 2.3.3: the fixed-size big-endian
+// encoding of x, left-padded with zeros to size bytes.
+func int2octets(x *big.Int, size int) []byte {
+	b := x.Bytes()
+	if len(b) >= size {
+		return b[len(b)-size:]
+	}
+
+	out := make([]byte, size)
+	copy(out[size-len(b):], b)
+
+	return out
+}
+
+func hmacSHA256(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+
+	return mac.Sum(nil)
+}
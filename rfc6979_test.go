@@ -0,0 +1,87 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+package evmsignature_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	evmsignature "github.com/BoostyLabs/evmsignature"
+)
+
+func TestSignDeterministicIsDeterministic(t *testing.T) {
+	privateKeyECDSA, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pk := evmsignature.PrivateKey(hex.EncodeToString(crypto.FromECDSA(privateKeyECDSA)))
+	hash := crypto.Keccak256([]byte("deterministic signing"))
+
+	r1, s1, v1, err := evmsignature.SignDeterministic(hash, pk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r2, s2, v2, err := evmsignature.SignDeterministic(hash, pk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r1.Cmp(r2) != 0 || s1.Cmp(s2) != 0 || v1 != v2 {
+		t.Fatalf("two calls with the same (pk, hash) produced different signatures: (%s,%s,%d) vs (%s,%s,%d)", r1, s1, v1, r2, s2, v2)
+	}
+}
+
+func TestSignDeterministicRecovers(t *testing.T) {
+	privateKeyECDSA, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pk := evmsignature.PrivateKey(hex.EncodeToString(crypto.FromECDSA(privateKeyECDSA)))
+	wantAddress := crypto.PubkeyToAddress(privateKeyECDSA.PublicKey)
+	hash := crypto.Keccak256([]byte("deterministic signing"))
+
+	r, s, v, err := evmsignature.SignDeterministic(hash, pk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sig := make([]byte, 65)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[64] = byte(v - evmsignature.PrivateKeyVTwentySeven)
+
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		t.Fatalf("failed to recover pubkey: %v", err)
+	}
+
+	if gotAddress := crypto.PubkeyToAddress(*pubKey); gotAddress != wantAddress {
+		t.Fatalf("recovered address = %s, want %s", gotAddress, wantAddress)
+	}
+}
+
+func TestSignDeterministicDifferentHashesDifferentSignatures(t *testing.T) {
+	privateKeyECDSA, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pk := evmsignature.PrivateKey(hex.EncodeToString(crypto.FromECDSA(privateKeyECDSA)))
+
+	r1, s1, _, err := evmsignature.SignDeterministic(crypto.Keccak256([]byte("a")), pk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r2, s2, _, err := evmsignature.SignDeterministic(crypto.Keccak256([]byte("b")), pk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r1.Cmp(r2) == 0 && s1.Cmp(s2) == 0 {
+		t.Fatal("signatures over different hashes must not collide")
+	}
+}
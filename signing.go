@@ -0,0 +1,50 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+package evmsignature
+
+import (
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/zeebo/errs"
+)
+
+// ErrSign indicates that sign error.
+var ErrSign = errs.Class("sign error")
+
+// sign signs hash with the private key and returns the 65-byte [R || S || V]
+// signature, with V following the package's PrivateKeyVTwentySeven/Eight convention.
+func sign(hash []byte, pk PrivateKey) ([]byte, error) {
+	privateKeyECDSA, err := crypto.HexToECDSA(string(pk))
+	if err != nil {
+		return nil, ErrSign.Wrap(err)
+	}
+
+	signature, err := crypto.Sign(hash, privateKeyECDSA)
+	if err != nil {
+		return nil, ErrSign.Wrap(err)
+	}
+
+	signature[64] += byte(PrivateKeyVTwentySeven)
+
+	return signature, nil
+}
+
+// recoverSigner recovers the address that produced sig over hash.
+func recoverSigner(hash, sig []byte) (Address, error) {
+	if Length(len(sig)*2) != LengthTwoBlockInputSignature {
+		return "", ErrSign.New("invalid signature length")
+	}
+
+	sigCopy := make([]byte, len(sig))
+	copy(sigCopy, sig)
+	if sigCopy[64] >= byte(PrivateKeyVTwentySeven) {
+		sigCopy[64] -= byte(PrivateKeyVTwentySeven)
+	}
+
+	pubKey, err := crypto.SigToPub(hash, sigCopy)
+	if err != nil {
+		return "", ErrSign.Wrap(err)
+	}
+
+	return Address(crypto.PubkeyToAddress(*pubKey).Hex()), nil
+}
@@ -0,0 +1,354 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+// Package tx builds RLP-encoded, signed EVM transactions ready for eth_sendRawTransaction.
+package tx
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/zeebo/errs"
+
+	evmsignature "github.com/BoostyLabs/evmsignature"
+)
+
+// ErrTx indicates that transaction error.
+var ErrTx = errs.Class("transaction error")
+
+// Type defines the EIP-2718 transaction envelope type.
+type Type byte
+
+const (
+	// TypeLegacy indicates a pre-EIP-2718 transaction with no envelope type byte.
+	TypeLegacy Type = 0x00
+	// TypeAccessList indicates an EIP-2930 transaction.
+	TypeAccessList Type = 0x01
+	// TypeDynamicFee indicates an EIP-1559 transaction.
+	TypeDynamicFee Type = 0x02
+)
+
+// AccessTuple is a single entry of an EIP-2930/EIP-1559 access list.
+type AccessTuple struct {
+	Address     evmsignature.Address
+	StorageKeys [][32]byte
+}
+
+// AccessList is the list of addresses and storage keys a transaction plans to access.
+type AccessList []AccessTuple
+
+// Tx describes the fields of a legacy, EIP-2930 or EIP-1559 transaction prior to signing.
+type Tx struct {
+	Type Type
+
+	ChainID *big.Int
+	Nonce   uint64
+
+	GasTipCap *big.Int // maxPriorityFeePerGas; EIP-1559 only.
+	GasFeeCap *big.Int // maxFeePerGas (EIP-1559) or gasPrice (legacy/EIP-2930).
+	Gas       uint64
+
+	To    evmsignature.Address
+	Value *big.Int
+	Data  evmsignature.Hex
+
+	AccessList AccessList
+}
+
+// NewLegacyTx creates an unsigned legacy (type 0x00) transaction. chainID may
+// be nil to produce a pre-EIP-155 transaction.
+func NewLegacyTx(chainID *big.Int, nonce uint64, gasPrice *big.Int, gas uint64, to evmsignature.Address, value *big.Int, data evmsignature.Hex) Tx {
+	return Tx{Type: TypeLegacy, ChainID: chainID, Nonce: nonce, GasFeeCap: gasPrice, Gas: gas, To: to, Value: value, Data: data}
+}
+
+// NewAccessListTx creates an unsigned EIP-2930 (type 0x01) transaction.
+func NewAccessListTx(chainID *big.Int, nonce uint64, gasPrice *big.Int, gas uint64, to evmsignature.Address, value *big.Int, data evmsignature.Hex, accessList AccessList) Tx {
+	return Tx{Type: TypeAccessList, ChainID: chainID, Nonce: nonce, GasFeeCap: gasPrice, Gas: gas, To: to, Value: value, Data: data, AccessList: accessList}
+}
+
+// NewDynamicFeeTx creates an unsigned EIP-1559 (type 0x02) transaction.
+func NewDynamicFeeTx(chainID *big.Int, nonce uint64, gasTipCap, gasFeeCap *big.Int, gas uint64, to evmsignature.Address, value *big.Int, data evmsignature.Hex, accessList AccessList) Tx {
+	return Tx{Type: TypeDynamicFee, ChainID: chainID, Nonce: nonce, GasTipCap: gasTipCap, GasFeeCap: gasFeeCap, Gas: gas, To: to, Value: value, Data: data, AccessList: accessList}
+}
+
+// Sign RLP-encodes t, signs it with pk, and returns both the keccak256
+// transaction hash and the 0x-prefixed raw signed transaction, ready for eth_sendRawTransaction.
+func Sign(t Tx, pk evmsignature.PrivateKey) (hash []byte, raw evmsignature.Hex, err error) {
+	signingPayload, err := encodeSigningPayload(t)
+	if err != nil {
+		return nil, "", err
+	}
+
+	privateKeyECDSA, err := crypto.HexToECDSA(string(pk))
+	if err != nil {
+		return nil, "", ErrTx.Wrap(err)
+	}
+
+	sig, err := crypto.Sign(crypto.Keccak256(signingPayload), privateKeyECDSA)
+	if err != nil {
+		return nil, "", ErrTx.Wrap(err)
+	}
+
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	recoveryID := sig[64]
+
+	var v *big.Int
+	switch t.Type {
+	case TypeLegacy:
+		if t.ChainID != nil && t.ChainID.Sign() > 0 {
+			v = evmsignature.EncodeV(recoveryID, t.ChainID)
+		} else {
+			v = big.NewInt(int64(recoveryID) + 27)
+		}
+	default:
+		v = big.NewInt(int64(recoveryID))
+	}
+
+	signedPayload, err := encodeSignedPayload(t, v, r, s)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return crypto.Keccak256(signedPayload), evmsignature.HexPrefix + evmsignature.Hex(hex.EncodeToString(signedPayload)), nil
+}
+
+// RecoverSender decodes rawTx (legacy, EIP-2930 or EIP-1559) and recovers the
+// address that signed it.
+func RecoverSender(rawTx evmsignature.Hex) (evmsignature.Address, error) {
+	raw, err := hexToBytes(rawTx)
+	if err != nil {
+		return "", err
+	}
+	if len(raw) == 0 {
+		return "", ErrTx.New("empty raw transaction")
+	}
+
+	switch Type(raw[0]) {
+	case TypeAccessList, TypeDynamicFee:
+		return recoverTypedSender(Type(raw[0]), raw[1:])
+	default:
+		return recoverLegacySender(raw)
+	}
+}
+
+func encodeSigningPayload(t Tx) ([]byte, error) {
+	data, err := hexToBytes(t.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.Type {
+	case TypeLegacy:
+		fields := []interface{}{t.Nonce, t.GasFeeCap, t.Gas, toAddressBytes(t.To), t.Value, data}
+		if t.ChainID != nil && t.ChainID.Sign() > 0 {
+			fields = append(fields, t.ChainID, uint(0), uint(0))
+		}
+		return rlp.EncodeToBytes(fields)
+
+	case TypeAccessList:
+		fields := []interface{}{t.ChainID, t.Nonce, t.GasFeeCap, t.Gas, toAddressBytes(t.To), t.Value, data, toRLPAccessList(t.AccessList)}
+		return envelope(t.Type, fields)
+
+	case TypeDynamicFee:
+		fields := []interface{}{t.ChainID, t.Nonce, t.GasTipCap, t.GasFeeCap, t.Gas, toAddressBytes(t.To), t.Value, data, toRLPAccessList(t.AccessList)}
+		return envelope(t.Type, fields)
+
+	default:
+		return nil, ErrTx.New("unknown transaction type %d", t.Type)
+	}
+}
+
+func encodeSignedPayload(t Tx, v, r, s *big.Int) ([]byte, error) {
+	data, err := hexToBytes(t.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.Type {
+	case TypeLegacy:
+		fields := []interface{}{t.Nonce, t.GasFeeCap, t.Gas, toAddressBytes(t.To), t.Value, data, v, r, s}
+		return rlp.EncodeToBytes(fields)
+
+	case TypeAccessList:
+		fields := []interface{}{t.ChainID, t.Nonce, t.GasFeeCap, t.Gas, toAddressBytes(t.To), t.Value, data, toRLPAccessList(t.AccessList), v, r, s}
+		return envelope(t.Type, fields)
+
+	case TypeDynamicFee:
+		fields := []interface{}{t.ChainID, t.Nonce, t.GasTipCap, t.GasFeeCap, t.Gas, toAddressBytes(t.To), t.Value, data, toRLPAccessList(t.AccessList), v, r, s}
+		return envelope(t.Type, fields)
+
+	default:
+		return nil, ErrTx.New("unknown transaction type %d", t.Type)
+	}
+}
+
+// envelope RLP-encodes fields and prefixes the result with the EIP-2718 type byte.
+func envelope(t Type, fields []interface{}) ([]byte, error) {
+	payload, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		return nil, ErrTx.Wrap(err)
+	}
+
+	return append([]byte{byte(t)}, payload...), nil
+}
+
+type rlpAccessTuple struct {
+	Address     [20]byte
+	StorageKeys [][32]byte
+}
+
+func toRLPAccessList(al AccessList) []rlpAccessTuple {
+	out := make([]rlpAccessTuple, len(al))
+	for i, a := range al {
+		out[i] = rlpAccessTuple{Address: common.HexToAddress(string(a.Address)), StorageKeys: a.StorageKeys}
+	}
+
+	return out
+}
+
+// toAddressBytes returns the RLP representation of a transaction's to field.
+// An empty address means "no recipient", i.e. contract creation, which RLP
+// encodes as a zero-length byte string rather than 20 zero bytes.
+func toAddressBytes(addr evmsignature.Address) []byte {
+	if addr == "" {
+		return []byte{}
+	}
+
+	return common.HexToAddress(string(addr)).Bytes()
+}
+
+func hexToBytes(h evmsignature.Hex) ([]byte, error) {
+	s := strings.TrimPrefix(string(h), string(evmsignature.HexPrefix))
+	if len(s)%2 != 0 {
+		s = "0" + s
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, ErrTx.Wrap(err)
+	}
+
+	return b, nil
+}
+
+type legacyFields struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	Gas      uint64
+	To       []byte
+	Value    *big.Int
+	Data     []byte
+	V        *big.Int
+	R        *big.Int
+	S        *big.Int
+}
+
+func recoverLegacySender(raw []byte) (evmsignature.Address, error) {
+	var decoded legacyFields
+	if err := rlp.DecodeBytes(raw, &decoded); err != nil {
+		return "", ErrTx.Wrap(err)
+	}
+
+	var recoveryID byte
+	var chainID *big.Int
+	if decoded.V.Cmp(big.NewInt(35)) >= 0 {
+		recoveryID, chainID = evmsignature.DecodeV(decoded.V)
+	} else {
+		recoveryID = byte(new(big.Int).Sub(decoded.V, big.NewInt(27)).Int64())
+	}
+
+	fields := []interface{}{decoded.Nonce, decoded.GasPrice, decoded.Gas, decoded.To, decoded.Value, decoded.Data}
+	if chainID != nil {
+		fields = append(fields, chainID, uint(0), uint(0))
+	}
+
+	signingPayload, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		return "", ErrTx.Wrap(err)
+	}
+
+	return recoverFromSig(signingPayload, recoveryID, decoded.R, decoded.S)
+}
+
+type accessListFields struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasPrice   *big.Int
+	Gas        uint64
+	To         []byte
+	Value      *big.Int
+	Data       []byte
+	AccessList []rlpAccessTuple
+	V          *big.Int
+	R          *big.Int
+	S          *big.Int
+}
+
+type dynamicFeeFields struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         []byte
+	Value      *big.Int
+	Data       []byte
+	AccessList []rlpAccessTuple
+	V          *big.Int
+	R          *big.Int
+	S          *big.Int
+}
+
+func recoverTypedSender(t Type, body []byte) (evmsignature.Address, error) {
+	switch t {
+	case TypeAccessList:
+		var decoded accessListFields
+		if err := rlp.DecodeBytes(body, &decoded); err != nil {
+			return "", ErrTx.Wrap(err)
+		}
+
+		fields := []interface{}{decoded.ChainID, decoded.Nonce, decoded.GasPrice, decoded.Gas, decoded.To, decoded.Value, decoded.Data, decoded.AccessList}
+		signingPayload, err := envelope(t, fields)
+		if err != nil {
+			return "", err
+		}
+
+		return recoverFromSig(signingPayload, byte(decoded.V.Int64()), decoded.R, decoded.S)
+
+	case TypeDynamicFee:
+		var decoded dynamicFeeFields
+		if err := rlp.DecodeBytes(body, &decoded); err != nil {
+			return "", ErrTx.Wrap(err)
+		}
+
+		fields := []interface{}{decoded.ChainID, decoded.Nonce, decoded.GasTipCap, decoded.GasFeeCap, decoded.Gas, decoded.To, decoded.Value, decoded.Data, decoded.AccessList}
+		signingPayload, err := envelope(t, fields)
+		if err != nil {
+			return "", err
+		}
+
+		return recoverFromSig(signingPayload, byte(decoded.V.Int64()), decoded.R, decoded.S)
+
+	default:
+		return "", ErrTx.New("unsupported transaction type %d", t)
+	}
+}
+
+func recoverFromSig(signingPayload []byte, recoveryID byte, r, s *big.Int) (evmsignature.Address, error) {
+	sig := make([]byte, 65)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[64] = recoveryID
+
+	pubKey, err := crypto.SigToPub(crypto.Keccak256(signingPayload), sig)
+	if err != nil {
+		return "", ErrTx.Wrap(err)
+	}
+
+	return evmsignature.Address(crypto.PubkeyToAddress(*pubKey).Hex()), nil
+}
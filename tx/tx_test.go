@@ -0,0 +1,95 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+package tx_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	evmsignature "github.com/BoostyLabs/evmsignature"
+	"github.com/BoostyLabs/evmsignature/tx"
+)
+
+func testKey(t *testing.T) (evmsignature.PrivateKey, evmsignature.Address) {
+	t.Helper()
+
+	privateKeyECDSA, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	pk := evmsignature.PrivateKey(hex.EncodeToString(crypto.FromECDSA(privateKeyECDSA)))
+	address := evmsignature.Address(crypto.PubkeyToAddress(privateKeyECDSA.PublicKey).Hex())
+
+	return pk, address
+}
+
+func TestSignRecoverRoundTrip(t *testing.T) {
+	pk, wantAddress := testKey(t)
+	to := evmsignature.Address("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC")
+	accessList := tx.AccessList{{Address: to, StorageKeys: [][32]byte{{1}}}}
+
+	for name, txn := range map[string]tx.Tx{
+		"legacy":      tx.NewLegacyTx(big.NewInt(1), 0, big.NewInt(1_000_000_000), 21000, to, big.NewInt(1), evmsignature.Hex("0x")),
+		"pre-eip-155": tx.NewLegacyTx(nil, 0, big.NewInt(1_000_000_000), 21000, to, big.NewInt(1), evmsignature.Hex("0x")),
+		"access-list": tx.NewAccessListTx(big.NewInt(1), 0, big.NewInt(1_000_000_000), 21000, to, big.NewInt(1), evmsignature.Hex("0x"), accessList),
+		"dynamic-fee": tx.NewDynamicFeeTx(big.NewInt(1), 0, big.NewInt(1), big.NewInt(1_000_000_000), 21000, to, big.NewInt(1), evmsignature.Hex("0x"), accessList),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, raw, err := tx.Sign(txn, pk)
+			if err != nil {
+				t.Fatalf("unexpected error signing: %v", err)
+			}
+
+			gotAddress, err := tx.RecoverSender(raw)
+			if err != nil {
+				t.Fatalf("unexpected error recovering: %v", err)
+			}
+
+			if gotAddress != wantAddress {
+				t.Fatalf("RecoverSender = %s, want %s", gotAddress, wantAddress)
+			}
+		})
+	}
+}
+
+func TestSignContractCreationOmitsTo(t *testing.T) {
+	pk, _ := testKey(t)
+	legacyTx := tx.NewLegacyTx(big.NewInt(1), 0, big.NewInt(1_000_000_000), 21000, evmsignature.Address(""), big.NewInt(0), evmsignature.Hex("0x600a600c60003960"))
+
+	_, raw, err := tx.Sign(legacyTx, pk)
+	if err != nil {
+		t.Fatalf("unexpected error signing: %v", err)
+	}
+
+	var decoded []interface{}
+	rawBytes, err := hex.DecodeString(strings.TrimPrefix(string(raw), string(evmsignature.HexPrefix)))
+	if err != nil {
+		t.Fatalf("failed to decode raw tx: %v", err)
+	}
+	if err := rlp.DecodeBytes(rawBytes, &decoded); err != nil {
+		t.Fatalf("failed to RLP-decode raw tx: %v", err)
+	}
+
+	to, ok := decoded[3].([]byte)
+	if !ok {
+		t.Fatalf("to field has unexpected type %T", decoded[3])
+	}
+	if len(to) != 0 {
+		t.Fatalf("contract-creation tx must RLP-encode an empty to field, got %x", to)
+	}
+
+	gotAddress, err := tx.RecoverSender(raw)
+	if err != nil {
+		t.Fatalf("unexpected error recovering: %v", err)
+	}
+	if gotAddress == "" {
+		t.Fatal("expected a recovered sender address")
+	}
+}
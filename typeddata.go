@@ -0,0 +1,402 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+package evmsignature
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/zeebo/errs"
+)
+
+// ErrTypedData indicates that typed data error.
+var ErrTypedData = errs.Class("typed data error")
+
+// Field describes one field of a TypedData type definition.
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedDataDomain describes the EIP-712 domain separator of a TypedData payload.
+type TypedDataDomain struct {
+	Name              string   `json:"name"`
+	Version           string   `json:"version"`
+	ChainId           *big.Int `json:"chainId"`
+	VerifyingContract Address  `json:"verifyingContract"`
+	Salt              string   `json:"salt,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting chainId as either a JSON
+// number or a 0x-prefixed hex string, since both appear in RPC payloads.
+func (d *TypedDataDomain) UnmarshalJSON(data []byte) error {
+	type alias TypedDataDomain
+	aux := &struct {
+		ChainId json.RawMessage `json:"chainId"`
+		*alias
+	}{alias: (*alias)(d)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return ErrTypedData.Wrap(err)
+	}
+
+	if len(aux.ChainId) == 0 || string(aux.ChainId) == "null" {
+		return nil
+	}
+
+	var chainIDStr string
+	if err := json.Unmarshal(aux.ChainId, &chainIDStr); err == nil {
+		n, ok := new(big.Int).SetString(strings.TrimPrefix(chainIDStr, "0x"), 16)
+		if !ok {
+			return ErrTypedData.New("invalid chainId %q", chainIDStr)
+		}
+		d.ChainId = n
+		return nil
+	}
+
+	n := new(big.Int)
+	if err := json.Unmarshal(aux.ChainId, n); err != nil {
+		return ErrTypedData.New("invalid chainId")
+	}
+	d.ChainId = n
+
+	return nil
+}
+
+// Map renders the domain as the field map expected under the "EIP712Domain" type.
+func (d TypedDataDomain) Map() map[string]interface{} {
+	m := map[string]interface{}{
+		"name":              d.Name,
+		"version":           d.Version,
+		"verifyingContract": d.VerifyingContract,
+	}
+	if d.ChainId != nil {
+		m["chainId"] = d.ChainId
+	}
+	if d.Salt != "" {
+		m["salt"] = d.Salt
+	}
+
+	return m
+}
+
+// TypedData describes an EIP-712 structured-data payload ready for hashing and signing.
+type TypedData struct {
+	Types       map[string][]Field     `json:"types"`
+	PrimaryType string                 `json:"primaryType"`
+	Domain      TypedDataDomain        `json:"domain"`
+	Message     map[string]interface{} `json:"message"`
+}
+
+// dependencies collects, into found, the names of every custom type primaryType
+// transitively references (primaryType itself included).
+func (td TypedData) dependencies(primaryType string, found map[string]bool) {
+	if found[primaryType] {
+		return
+	}
+
+	fields, ok := td.Types[primaryType]
+	if !ok {
+		return
+	}
+	found[primaryType] = true
+
+	for _, field := range fields {
+		elemType, _, isArray := isArrayType(field.Type)
+		t := field.Type
+		if isArray {
+			t = elemType
+		}
+		if _, ok := td.Types[t]; ok {
+			td.dependencies(t, found)
+		}
+	}
+}
+
+// encodeType builds the EIP-712 type string for primaryType: its own
+// "Name(type1 field1,...)" signature followed by every referenced custom
+// struct type, sorted alphabetically.
+func (td TypedData) encodeType(primaryType string) (string, error) {
+	if _, ok := td.Types[primaryType]; !ok {
+		return "", ErrTypedData.New("unknown type %q", primaryType)
+	}
+
+	deps := make(map[string]bool)
+	td.dependencies(primaryType, deps)
+	delete(deps, primaryType)
+
+	sortedDeps := make([]string, 0, len(deps))
+	for dep := range deps {
+		sortedDeps = append(sortedDeps, dep)
+	}
+	sort.Strings(sortedDeps)
+
+	var b strings.Builder
+	b.WriteString(encodeTypeFields(primaryType, td.Types[primaryType]))
+	for _, dep := range sortedDeps {
+		b.WriteString(encodeTypeFields(dep, td.Types[dep]))
+	}
+
+	return b.String(), nil
+}
+
+func encodeTypeFields(name string, fields []Field) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field.Type + " " + field.Name
+	}
+
+	return name + "(" + strings.Join(parts, ",") + ")"
+}
+
+// TypeHash returns keccak256(encodeType(primaryType)).
+func (td TypedData) TypeHash(primaryType string) ([]byte, error) {
+	encType, err := td.encodeType(primaryType)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Keccak256([]byte(encType)), nil
+}
+
+// encodeData ABI-encodes the fields of primaryType, prefixed by its type hash.
+func (td TypedData) encodeData(primaryType string, data map[string]interface{}) ([]byte, error) {
+	typeHash, err := td.TypeHash(primaryType)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, ok := td.Types[primaryType]
+	if !ok {
+		return nil, ErrTypedData.New("unknown type %q", primaryType)
+	}
+
+	encoded := append([]byte{}, typeHash...)
+	for _, field := range fields {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, ErrTypedData.New("missing value for field %q", field.Name)
+		}
+
+		encodedValue, err := td.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, encodedValue...)
+	}
+
+	return encoded, nil
+}
+
+// HashStruct computes the EIP-712 struct hash of data under primaryType:
+// keccak256(encodeData(primaryType, data)), whose input already begins with
+// the type hash.
+func (td TypedData) HashStruct(primaryType string, data map[string]interface{}) ([]byte, error) {
+	encoded, err := td.encodeData(primaryType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Keccak256(encoded), nil
+}
+
+// encodeValue ABI-encodes a single field value as 32 bytes: address, uintN,
+// intN and bool left-padded, bytesN right-padded, dynamic types and nested
+// structs/arrays hashed.
+func (td TypedData) encodeValue(typ string, value interface{}) ([]byte, error) {
+	if elemType, _, isArray := isArrayType(typ); isArray {
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, ErrTypedData.New("expected array value for type %q", typ)
+		}
+
+		var concatenated []byte
+		for _, v := range values {
+			encodedValue, err := td.encodeValue(elemType, v)
+			if err != nil {
+				return nil, err
+			}
+			concatenated = append(concatenated, encodedValue...)
+		}
+
+		return crypto.Keccak256(concatenated), nil
+	}
+
+	if _, ok := td.Types[typ]; ok {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, ErrTypedData.New("expected struct value for type %q", typ)
+		}
+		return td.HashStruct(typ, m)
+	}
+
+	switch {
+	case typ == "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, ErrTypedData.New("expected string value for type %q", typ)
+		}
+		return crypto.Keccak256([]byte(s)), nil
+
+	case typ == "bytes":
+		b, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(b), nil
+
+	case typ == "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, ErrTypedData.New("expected bool value for type %q", typ)
+		}
+		if b {
+			return common.LeftPadBytes([]byte{1}, 32), nil
+		}
+		return make([]byte, 32), nil
+
+	case typ == "address":
+		addr, ok := value.(Address)
+		if !ok {
+			s, isStr := value.(string)
+			if !isStr {
+				return nil, ErrTypedData.New("expected address value for type %q", typ)
+			}
+			addr = Address(s)
+		}
+		return common.LeftPadBytes(common.HexToAddress(string(addr)).Bytes(), 32), nil
+
+	case strings.HasPrefix(typ, "uint"), strings.HasPrefix(typ, "int"):
+		n, err := toBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		return math.U256Bytes(n), nil
+
+	case strings.HasPrefix(typ, "bytes"):
+		b, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return common.RightPadBytes(b, 32), nil
+
+	default:
+		return nil, ErrTypedData.New("unsupported type %q", typ)
+	}
+}
+
+// Hash computes the final EIP-712 digest:
+// keccak256(0x1901 || domainSeparator || hashStruct(PrimaryType, Message)).
+func (td TypedData) Hash() ([]byte, error) {
+	domainSeparator, err := td.HashStruct("EIP712Domain", td.Domain.Map())
+	if err != nil {
+		return nil, err
+	}
+
+	messageHash, err := td.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := append([]byte{0x19, 0x01}, domainSeparator...)
+	payload = append(payload, messageHash...)
+
+	return crypto.Keccak256(payload), nil
+}
+
+// SignTypedData signs td's EIP-712 digest with the private key.
+func SignTypedData(pk PrivateKey, td TypedData) ([]byte, error) {
+	digest, err := td.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	return sign(digest, pk)
+}
+
+// RecoverTypedDataSigner recovers the address that produced sig over td's EIP-712 digest.
+func RecoverTypedDataSigner(td TypedData, sig []byte) (Address, error) {
+	digest, err := td.Hash()
+	if err != nil {
+		return "", err
+	}
+
+	return recoverSigner(digest, sig)
+}
+
+// isArrayType reports whether typ is an array type ("T[]" or "T[N]"), returning
+// its element type and fixed length (-1 for a dynamic array).
+func isArrayType(typ string) (elemType string, fixedLen int, isArray bool) {
+	idx := strings.LastIndex(typ, "[")
+	if idx == -1 || !strings.HasSuffix(typ, "]") {
+		return "", 0, false
+	}
+
+	lenStr := typ[idx+1 : len(typ)-1]
+	if lenStr == "" {
+		return typ[:idx], -1, true
+	}
+
+	n, err := strconv.Atoi(lenStr)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return typ[:idx], n, true
+}
+
+// toBigInt converts a JSON-decoded or directly-supplied numeric value to *big.Int.
+func toBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, ErrTypedData.New("integer value must not be nil")
+		}
+		return v, nil
+	case int64:
+		return big.NewInt(v), nil
+	case float64:
+		return big.NewInt(int64(v)), nil
+	case string:
+		base := 10
+		s := v
+		if strings.HasPrefix(v, "0x") {
+			base = 16
+			s = v[2:]
+		}
+		n, ok := new(big.Int).SetString(s, base)
+		if !ok {
+			return nil, ErrTypedData.New("invalid integer value %q", v)
+		}
+		return n, nil
+	default:
+		return nil, ErrTypedData.New("unsupported integer value %T", value)
+	}
+}
+
+// toBytes converts a JSON-decoded or directly-supplied value to raw bytes.
+func toBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		if strings.HasPrefix(v, "0x") {
+			b, err := hex.DecodeString(v[2:])
+			if err != nil {
+				return nil, ErrTypedData.Wrap(err)
+			}
+			return b, nil
+		}
+		return []byte(v), nil
+	default:
+		return nil, ErrTypedData.New("unsupported bytes value %T", value)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2021 Creditor Corp. Group.
+// See LICENSE for copying information.
+
+package evmsignature_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	evmsignature "github.com/BoostyLabs/evmsignature"
+)
+
+// mailTypedData builds the canonical "Mail" example from the EIP-712 spec.
+func mailTypedData() evmsignature.TypedData {
+	return evmsignature.TypedData{
+		Types: map[string][]evmsignature.Field{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Person": {
+				{Name: "name", Type: "string"},
+				{Name: "wallet", Type: "address"},
+			},
+			"Mail": {
+				{Name: "from", Type: "Person"},
+				{Name: "to", Type: "Person"},
+				{Name: "contents", Type: "string"},
+			},
+		},
+		PrimaryType: "Mail",
+		Domain: evmsignature.TypedDataDomain{
+			Name:              "Ether Mail",
+			Version:           "1",
+			ChainId:           big.NewInt(1),
+			VerifyingContract: evmsignature.Address("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"),
+		},
+		Message: map[string]interface{}{
+			"from": map[string]interface{}{
+				"name":   "Cow",
+				"wallet": evmsignature.Address("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"),
+			},
+			"to": map[string]interface{}{
+				"name":   "Bob",
+				"wallet": evmsignature.Address("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"),
+			},
+			"contents": "Hello, Bob!",
+		},
+	}
+}
+
+func TestHashMailExample(t *testing.T) {
+	td := mailTypedData()
+
+	got, err := td.Hash()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want, err := hex.DecodeString("be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hex.EncodeToString(got) != hex.EncodeToString(want) {
+		t.Fatalf("Hash() = %x, want %x", got, want)
+	}
+}
+
+func TestHashNilChainIDWithDeclaredFieldFails(t *testing.T) {
+	td := mailTypedData()
+	td.Domain.ChainId = nil
+
+	if _, err := td.Hash(); err == nil {
+		t.Fatal("expected an error because EIP712Domain declares chainId but the domain has none, got nil")
+	}
+}